@@ -0,0 +1,124 @@
+// Package bitrot provides a fast bitrot-detection checksum over streamed
+// data, built on keyed HighwayHash-256, in the spirit of how object
+// stores such as MinIO checksum erasure-coded shards on write and
+// re-verify them on read.
+package bitrot
+
+import (
+	"fmt"
+	"hash"
+	"io"
+
+	highway "github.com/disruptive-tech-community/go-highway"
+)
+
+// DefaultKey is a nothing-up-my-sleeve key built from the fractional hex
+// digits of e (0xb7e151628aed2a6a...), the same constant RC5 uses for its
+// P-array seed, so callers get a deterministic keyed hash without having
+// to invent their own key material.
+var DefaultKey = highway.Lanes{
+	0xb7e151628aed2a6a,
+	0xbf7158809cf4f3c7,
+	0x62e7160f38b4da56,
+	0xa784d9045190cfef,
+}
+
+// Verifier computes per-shard HighwayHash-256 tags as data streams
+// through Write, so that Tags can be persisted alongside the data and
+// later handed to Verify to detect divergent shards.
+type Verifier struct {
+	key       highway.Lanes
+	shardSize int
+	buf       []byte
+	tags      [][4]uint64
+}
+
+// NewVerifier returns a Verifier that tags every shardSize bytes written
+// to it with a keyed HighwayHash-256. shardSize must be positive.
+func NewVerifier(key highway.Lanes, shardSize int) (*Verifier, error) {
+	if shardSize <= 0 {
+		return nil, fmt.Errorf("bitrot: shardSize must be positive, got %d", shardSize)
+	}
+	return &Verifier{
+		key:       key,
+		shardSize: shardSize,
+		buf:       make([]byte, 0, shardSize),
+	}, nil
+}
+
+func (v *Verifier) Write(p []byte) (int, error) {
+	n := len(p)
+
+	for len(p) > 0 {
+		need := v.shardSize - len(v.buf)
+		if need > len(p) {
+			v.buf = append(v.buf, p...)
+			return n, nil
+		}
+
+		v.buf = append(v.buf, p[:need]...)
+		v.tags = append(v.tags, highway.Hash256(v.key, v.buf))
+		v.buf = v.buf[:0]
+		p = p[need:]
+	}
+
+	return n, nil
+}
+
+// Tags returns the HighwayHash-256 tag of every complete shard seen so
+// far, plus a final tag for any trailing partial shard. Once a trailing
+// shard has been tagged it is not tagged again on a later call.
+func (v *Verifier) Tags() [][4]uint64 {
+	if len(v.buf) > 0 {
+		v.tags = append(v.tags, highway.Hash256(v.key, v.buf))
+		v.buf = v.buf[:0]
+	}
+	return v.tags
+}
+
+// MismatchError reports the byte offsets of shards whose tag no longer
+// matches the data Verify read.
+type MismatchError struct {
+	Offsets []int64
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("bitrot: %d shard(s) diverged from their stored tag", len(e.Offsets))
+}
+
+// Verify re-hashes r shard by shard against tags, returning a
+// *MismatchError naming the offsets of any shard whose data no longer
+// matches its tag. It reads exactly len(tags) shards of shardSize bytes
+// (the last may be shorter) and returns early on any other read error.
+func (v *Verifier) Verify(r io.Reader, tags [][4]uint64) error {
+	buf := make([]byte, v.shardSize)
+	var mismatches []int64
+
+	for i, want := range tags {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if got := highway.Hash256(v.key, buf[:n]); got != want {
+				mismatches = append(mismatches, int64(i)*int64(v.shardSize))
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &MismatchError{Offsets: mismatches}
+	}
+	return nil
+}
+
+// NewShardHasher returns a hash.Hash computing a keyed HighwayHash-256
+// tag, for callers that want to fold shard tagging into an existing
+// io.MultiWriter pipeline instead of using Verifier directly. Call Sum
+// and then Reset at each shard boundary.
+func NewShardHasher(key highway.Lanes) hash.Hash {
+	return highway.New256(key)
+}
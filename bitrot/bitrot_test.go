@@ -0,0 +1,88 @@
+package bitrot
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVerifierRoundTrip(t *testing.T) {
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	v, err := NewVerifier(DefaultKey, 32)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	if _, err := v.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	tags := v.Tags()
+	if len(tags) != 4 {
+		t.Fatalf("len(Tags()) = %d, want 4 (three full 32-byte shards plus one 4-byte partial shard)", len(tags))
+	}
+
+	verifier, err := NewVerifier(DefaultKey, 32)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	if err := verifier.Verify(bytes.NewReader(data), tags); err != nil {
+		t.Errorf("Verify on unmodified data: %v", err)
+	}
+}
+
+func TestVerifierDetectsBitrot(t *testing.T) {
+	data := make([]byte, 96)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	v, err := NewVerifier(DefaultKey, 32)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	if _, err := v.Write(data); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	tags := v.Tags()
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[40] ^= 0xff // flips a byte in the second 32-byte shard
+
+	verifier, err := NewVerifier(DefaultKey, 32)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	err = verifier.Verify(bytes.NewReader(corrupted), tags)
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Verify on corrupted data: err = %v, want *MismatchError", err)
+	}
+	if want := []int64{32}; !int64SlicesEqual(mismatch.Offsets, want) {
+		t.Errorf("MismatchError.Offsets = %v, want %v", mismatch.Offsets, want)
+	}
+}
+
+func int64SlicesEqual(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestNewVerifierRejectsNonPositiveShardSize(t *testing.T) {
+	if _, err := NewVerifier(DefaultKey, 0); err == nil {
+		t.Error("NewVerifier(shardSize=0) = nil error, want error")
+	}
+	if _, err := NewVerifier(DefaultKey, -1); err == nil {
+		t.Error("NewVerifier(shardSize=-1) = nil error, want error")
+	}
+}
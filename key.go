@@ -0,0 +1,69 @@
+package highway
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// KeyFromBytes decodes b into a Lanes key. b must be exactly 32 bytes,
+// little-endian, matching the reference implementation's key layout.
+func KeyFromBytes(b []byte) (Lanes, error) {
+	if len(b) != packetSize {
+		return Lanes{}, fmt.Errorf("highway: key must be %d bytes, got %d", packetSize, len(b))
+	}
+
+	return Lanes{
+		binary.LittleEndian.Uint64(b[0:]),
+		binary.LittleEndian.Uint64(b[8:]),
+		binary.LittleEndian.Uint64(b[16:]),
+		binary.LittleEndian.Uint64(b[24:]),
+	}, nil
+}
+
+// KeyFromHex decodes s, a hex-encoded 32-byte key, into a Lanes key.
+func KeyFromHex(s string) (Lanes, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return Lanes{}, fmt.Errorf("highway: %w", err)
+	}
+	return KeyFromBytes(b)
+}
+
+// Bytes encodes k back into the 32-byte little-endian layout KeyFromBytes
+// expects.
+func (k Lanes) Bytes() []byte {
+	b := make([]byte, packetSize)
+	binary.LittleEndian.PutUint64(b[0:], k[0])
+	binary.LittleEndian.PutUint64(b[8:], k[1])
+	binary.LittleEndian.PutUint64(b[16:], k[2])
+	binary.LittleEndian.PutUint64(b[24:], k[3])
+	return b
+}
+
+// Equal reports whether a and b are equal, in constant time. Use it
+// instead of == when comparing HighwayHash-64 outputs used as a MAC, so
+// verification cannot leak timing information about how many leading
+// bytes matched.
+func Equal(a, b uint64) bool {
+	return subtle.ConstantTimeCompare(lanesBytes(a), lanesBytes(b)) == 1
+}
+
+// Equal128 is Equal for Hash128 outputs.
+func Equal128(a, b [2]uint64) bool {
+	return subtle.ConstantTimeCompare(lanesBytes(a[0], a[1]), lanesBytes(b[0], b[1])) == 1
+}
+
+// Equal256 is Equal for Hash256 outputs.
+func Equal256(a, b [4]uint64) bool {
+	return subtle.ConstantTimeCompare(lanesBytes(a[0], a[1], a[2], a[3]), lanesBytes(b[0], b[1], b[2], b[3])) == 1
+}
+
+func lanesBytes(lanes ...uint64) []byte {
+	b := make([]byte, 8*len(lanes))
+	for i, lane := range lanes {
+		binary.LittleEndian.PutUint64(b[i*8:], lane)
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package highway
+
+import "testing"
+
+func TestKeyFromBytesRoundTrip(t *testing.T) {
+	want := Lanes{0x0706050403020100, 0x0f0e0d0c0b0a0908, 0x1716151413121110, 0x1f1e1d1c1b1a1918}
+
+	got, err := KeyFromBytes(want.Bytes())
+	if err != nil {
+		t.Fatalf("KeyFromBytes: %v", err)
+	}
+	if got != want {
+		t.Errorf("KeyFromBytes(want.Bytes()) = %#v, want %#v", got, want)
+	}
+}
+
+func TestKeyFromBytesRejectsWrongLength(t *testing.T) {
+	for _, n := range []int{0, 16, 31, 33} {
+		if _, err := KeyFromBytes(make([]byte, n)); err == nil {
+			t.Errorf("KeyFromBytes(%d bytes) = nil error, want error", n)
+		}
+	}
+}
+
+func TestKeyFromHexRoundTrip(t *testing.T) {
+	want := Lanes{0x0706050403020100, 0x0f0e0d0c0b0a0908, 0x1716151413121110, 0x1f1e1d1c1b1a1918}
+
+	got, err := KeyFromHex("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	if err != nil {
+		t.Fatalf("KeyFromHex: %v", err)
+	}
+	if got != want {
+		t.Errorf("KeyFromHex(...) = %#v, want %#v", got, want)
+	}
+}
+
+func TestKeyFromHexRejectsInvalidInput(t *testing.T) {
+	if _, err := KeyFromHex("not hex"); err == nil {
+		t.Error("KeyFromHex(\"not hex\") = nil error, want error")
+	}
+	if _, err := KeyFromHex("0001"); err == nil {
+		t.Error("KeyFromHex(too short) = nil error, want error")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := Hash(vectorKey, vectorData(8))
+	b := Hash(vectorKey, vectorData(8))
+	c := Hash(vectorKey, vectorData(9))
+
+	if !Equal(a, b) {
+		t.Error("Equal(a, b) = false for identical hashes, want true")
+	}
+	if Equal(a, c) {
+		t.Error("Equal(a, c) = true for different hashes, want false")
+	}
+}
+
+func TestEqual128(t *testing.T) {
+	a := Hash128(vectorKey, vectorData(8))
+	b := Hash128(vectorKey, vectorData(8))
+	c := Hash128(vectorKey, vectorData(9))
+
+	if !Equal128(a, b) {
+		t.Error("Equal128(a, b) = false for identical hashes, want true")
+	}
+	if Equal128(a, c) {
+		t.Error("Equal128(a, c) = true for different hashes, want false")
+	}
+}
+
+func TestEqual256(t *testing.T) {
+	a := Hash256(vectorKey, vectorData(8))
+	b := Hash256(vectorKey, vectorData(8))
+	c := Hash256(vectorKey, vectorData(9))
+
+	if !Equal256(a, b) {
+		t.Error("Equal256(a, b) = false for identical hashes, want true")
+	}
+	if Equal256(a, c) {
+		t.Error("Equal256(a, c) = true for different hashes, want false")
+	}
+}
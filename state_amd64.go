@@ -0,0 +1,32 @@
+//go:build amd64
+
+package highway
+
+import (
+	"golang.org/x/sys/cpu"
+)
+
+// updateAVX2 is the AVX2 backend for state.Update, implemented in
+// state_amd64.s. state.Update guarantees packets is exactly one
+// packetSize-byte packet; the assembly still loops over packetSize
+// strides so it also behaves correctly if ever called directly with a
+// longer, packetSize-aligned buffer.
+//
+// It operates on the four lanes of v0/v1/mul0/mul1 packed into a single
+// YMM register each:
+//   - the per-lane add/xor chain is three VPADDQ and two VPXOR over all
+//     lanes at once instead of a Go for loop;
+//   - the 32x32->64 cross multiply uses VPMULUDQ directly on the low 32
+//     bits of each 64-bit lane, which is exactly the bit range VPMULUDQ
+//     reads, so no explicit mask0xFFFFFFFF step is needed;
+//   - ZipperMerge's byte shuffle collapses to one VPSHUFB per input using
+//     zipperMergeMask, since the scalar table already operates
+//     independently on each 128-bit half of the 32-byte block, matching
+//     VPSHUFB's per-128-bit-lane semantics.
+func updateAVX2(s *state, packets []byte)
+
+func init() {
+	if cpu.X86.HasAVX2 {
+		updatePacket = updateAVX2
+	}
+}
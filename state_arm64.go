@@ -0,0 +1,30 @@
+//go:build arm64
+
+package highway
+
+import (
+	"golang.org/x/sys/cpu"
+)
+
+// updateNEON is the NEON backend for state.Update, implemented in
+// state_arm64.s. state.Update guarantees packets is exactly one
+// packetSize-byte packet; the assembly still loops over packetSize
+// strides so it also behaves correctly if ever called directly with a
+// longer, packetSize-aligned buffer.
+//
+// NEON vectors are 128 bits wide (two uint64 lanes), half the width of
+// an AVX2 YMM register, so v0/v1/mul0/mul1 are each split into two V2D
+// halves covering lanes {0,1} and {2,3}. Each half then maps directly
+// onto one "half" iteration of the scalar ZipperMerge loop in
+// state_generic.go, so TBL with zipperMergeIndex reproduces it in one
+// instruction per half. The 32x32->64 cross multiply has no single-
+// instruction NEON equivalent of VPMULUDQ: XTN narrows each operand's
+// low 32 bits of every 64-bit lane into a 2S register, and UMULL widens
+// the product back to 2D.
+func updateNEON(s *state, packets []byte)
+
+func init() {
+	if cpu.ARM64.HasASIMD {
+		updatePacket = updateNEON
+	}
+}
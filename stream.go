@@ -0,0 +1,188 @@
+package highway
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+var marshalMagic = []byte("hwy1")
+
+// digest implements the streaming hash.Hash64 / hash.Hash interfaces for
+// HighwayHash-64, HighwayHash-128 and HighwayHash-256. Input is buffered
+// up to one packet at a time so callers can Write incrementally instead
+// of materializing the whole message for Hash/Hash128/Hash256.
+type digest struct {
+	key    Lanes
+	s      state
+	buf    [packetSize]byte
+	nbuf   int
+	length uint64
+	width  int
+}
+
+// New64 returns a new hash.Hash64 computing HighwayHash-64 under key.
+func New64(key Lanes) hash.Hash64 {
+	return newDigest(key, 8)
+}
+
+// New128 returns a new hash.Hash computing HighwayHash-128 under key.
+func New128(key Lanes) hash.Hash {
+	return newDigest(key, 16)
+}
+
+// New256 returns a new hash.Hash computing HighwayHash-256 under key.
+func New256(key Lanes) hash.Hash {
+	return newDigest(key, 32)
+}
+
+func newDigest(key Lanes, width int) *digest {
+	d := &digest{key: key, width: width}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.s = newstate(d.key)
+	d.nbuf = 0
+	d.length = 0
+}
+
+func (d *digest) Size() int      { return d.width }
+func (d *digest) BlockSize() int { return packetSize }
+
+func (d *digest) Write(p []byte) (int, error) {
+	n := len(p)
+	d.length += uint64(n)
+
+	if d.nbuf > 0 {
+		need := packetSize - d.nbuf
+		if len(p) < need {
+			d.nbuf += copy(d.buf[d.nbuf:], p)
+			return n, nil
+		}
+		copy(d.buf[d.nbuf:], p[:need])
+		d.s.Update(d.buf[:])
+		p = p[need:]
+		d.nbuf = 0
+	}
+
+	for len(p) >= packetSize {
+		d.s.Update(p[:packetSize])
+		p = p[packetSize:]
+	}
+
+	d.nbuf = copy(d.buf[:], p)
+	return n, nil
+}
+
+// finalState replays the length-tagged final packet handling inlined in
+// Hash/Hash128/Hash256 against a copy of the accumulated state, leaving d
+// untouched so Sum can be called repeatedly and interleaved with Write.
+func (d *digest) finalState() state {
+	s := d.s
+
+	remainder := d.nbuf
+	remainderMod4 := remainder & 3
+	packet4 := uint32(d.length) << 24
+	finalBytes := d.buf[remainder-remainderMod4 : remainder]
+	for i := 0; i < remainderMod4; i++ {
+		packet4 += uint32(finalBytes[i]) << uint(i*8)
+	}
+
+	var finalPacket [packetSize]byte
+	copy(finalPacket[:], d.buf[:remainder-remainderMod4])
+	binary.LittleEndian.PutUint32(finalPacket[packetSize-4:], packet4)
+
+	s.Update(finalPacket[:])
+	return s
+}
+
+func (d *digest) Sum64() uint64 {
+	s := d.finalState()
+	return s.Finalize()
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	switch d.width {
+	case 8:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], d.Sum64())
+		return append(b, buf[:]...)
+
+	case 16:
+		s := d.finalState()
+		out := s.Finalize128()
+		var buf [16]byte
+		binary.LittleEndian.PutUint64(buf[0:], out[0])
+		binary.LittleEndian.PutUint64(buf[8:], out[1])
+		return append(b, buf[:]...)
+
+	default:
+		s := d.finalState()
+		out := s.Finalize256()
+		var buf [32]byte
+		binary.LittleEndian.PutUint64(buf[0:], out[0])
+		binary.LittleEndian.PutUint64(buf[8:], out[1])
+		binary.LittleEndian.PutUint64(buf[16:], out[2])
+		binary.LittleEndian.PutUint64(buf[24:], out[3])
+		return append(b, buf[:]...)
+	}
+}
+
+// MarshalBinary checkpoints the digest's four Lanes and pending buffer so
+// a long-lived hashing session can be persisted and resumed later. It does
+// not encode the key: callers restore into a digest already constructed
+// with New64/New128/New256 under the same key.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, len(marshalMagic)+1+8+1+packetSize+4*4*8)
+	b = append(b, marshalMagic...)
+	b = append(b, byte(d.width))
+
+	var lengthBuf [8]byte
+	binary.LittleEndian.PutUint64(lengthBuf[:], d.length)
+	b = append(b, lengthBuf[:]...)
+
+	b = append(b, byte(d.nbuf))
+	b = append(b, d.buf[:]...)
+
+	for _, lanes := range []Lanes{d.s.v0, d.s.v1, d.s.mul0, d.s.mul1} {
+		var laneBuf [8]byte
+		for _, lane := range lanes {
+			binary.LittleEndian.PutUint64(laneBuf[:], lane)
+			b = append(b, laneBuf[:]...)
+		}
+	}
+
+	return b, nil
+}
+
+func (d *digest) UnmarshalBinary(b []byte) error {
+	if len(b) != len(marshalMagic)+1+8+1+packetSize+4*4*8 {
+		return errInvalidState
+	}
+	if string(b[:len(marshalMagic)]) != string(marshalMagic) {
+		return errInvalidState
+	}
+	b = b[len(marshalMagic):]
+
+	d.width, b = int(b[0]), b[1:]
+	d.length, b = binary.LittleEndian.Uint64(b), b[8:]
+	d.nbuf, b = int(b[0]), b[1:]
+	copy(d.buf[:], b[:packetSize])
+	b = b[packetSize:]
+
+	for _, lanes := range []*Lanes{&d.s.v0, &d.s.v1, &d.s.mul0, &d.s.mul1} {
+		for i := range lanes {
+			lanes[i] = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		}
+	}
+
+	return nil
+}
+
+type stateError string
+
+func (e stateError) Error() string { return string(e) }
+
+const errInvalidState = stateError("highway: invalid hash state")
@@ -0,0 +1,138 @@
+package highway
+
+import (
+	"bytes"
+	"testing"
+)
+
+// streamKey is distinct from vectorKey so these tests don't accidentally
+// pass by reusing vectorData/vectorKey's exact byte patterns.
+var streamKey = Lanes{0x1f1e1d1c1b1a1918, 0x1716151413121110, 0x0f0e0d0c0b0a0908, 0x0706050403020100}
+
+// writeInChunks feeds data to w in pieces of chunk bytes (the last piece
+// may be shorter), to exercise Write boundaries that don't line up with
+// packetSize (32).
+func writeInChunks(w interface{ Write([]byte) (int, error) }, data []byte, chunk int) {
+	for len(data) > 0 {
+		n := chunk
+		if n > len(data) {
+			n = len(data)
+		}
+		w.Write(data[:n])
+		data = data[n:]
+	}
+}
+
+func TestStreamMatchesOneShot(t *testing.T) {
+	sizes := []int{0, 1, 31, 32, 33, 63, 64, 65, 127, 200}
+	chunks := []int{1, 31, 32, 33}
+
+	for _, n := range sizes {
+		data := vectorData(n)
+		want64 := Hash(streamKey, data)
+		want128 := Hash128(streamKey, data)
+		want256 := Hash256(streamKey, data)
+
+		for _, chunk := range chunks {
+			d64 := New64(streamKey)
+			writeInChunks(d64, data, chunk)
+			if got := d64.Sum64(); got != want64 {
+				t.Errorf("New64 len=%d chunk=%d: Sum64() = %#x, want %#x", n, chunk, got, want64)
+			}
+
+			d128 := New128(streamKey)
+			writeInChunks(d128, data, chunk)
+			got128 := d128.Sum(nil)
+			want128Bytes := lanesBytes(want128[0], want128[1])
+			if !bytes.Equal(got128, want128Bytes) {
+				t.Errorf("New128 len=%d chunk=%d: Sum() = %x, want %x", n, chunk, got128, want128Bytes)
+			}
+
+			d256 := New256(streamKey)
+			writeInChunks(d256, data, chunk)
+			got256 := d256.Sum(nil)
+			want256Bytes := lanesBytes(want256[0], want256[1], want256[2], want256[3])
+			if !bytes.Equal(got256, want256Bytes) {
+				t.Errorf("New256 len=%d chunk=%d: Sum() = %x, want %x", n, chunk, got256, want256Bytes)
+			}
+		}
+	}
+}
+
+func TestDigestMarshalRoundTrip(t *testing.T) {
+	data := vectorData(100)
+	first, second := data[:37], data[37:]
+
+	want := New256(streamKey)
+	want.Write(data)
+	wantSum := want.Sum(nil)
+
+	d := New256(streamKey)
+	d.Write(first)
+
+	state, err := d.(*digest).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	resumed := New256(streamKey).(*digest)
+	if err := resumed.UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	resumed.Write(second)
+
+	if got := resumed.Sum(nil); !bytes.Equal(got, wantSum) {
+		t.Errorf("resumed digest Sum() = %x, want %x", got, wantSum)
+	}
+}
+
+func TestUnmarshalBinaryRejectsMalformed(t *testing.T) {
+	d := New64(streamKey).(*digest)
+	if err := d.UnmarshalBinary([]byte("too short")); err != errInvalidState {
+		t.Errorf("UnmarshalBinary(short) error = %v, want %v", err, errInvalidState)
+	}
+
+	state, _ := New64(streamKey).(*digest).MarshalBinary()
+	state[0] ^= 0xff
+	if err := d.UnmarshalBinary(state); err != errInvalidState {
+		t.Errorf("UnmarshalBinary(bad magic) error = %v, want %v", err, errInvalidState)
+	}
+}
+
+// TestUnmarshalBinaryDoesNotRestoreKey documents a footgun: UnmarshalBinary
+// restores v0/v1/mul0/mul1 but not the key used to derive them, so a
+// digest's d.key only stays correct if the caller unmarshals into a digest
+// constructed (via New64/New128/New256) with the same key that produced
+// the marshaled state. Unmarshaling into a digest built with a different
+// key leaves Write/Sum correct (they only touch the restored state) but
+// Reset will reinitialize from the wrong key.
+func TestUnmarshalBinaryDoesNotRestoreKey(t *testing.T) {
+	data := vectorData(50)
+
+	original := New256(streamKey).(*digest)
+	original.Write(data)
+	state, _ := original.MarshalBinary()
+
+	otherKey := Lanes{0xdead, 0xbeef, 0xcafe, 0xbabe}
+	mismatched := New256(otherKey).(*digest)
+	if err := mismatched.UnmarshalBinary(state); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+
+	// The restored state is correct regardless of the digest's own key...
+	wantOut := Hash256(streamKey, data)
+	want := lanesBytes(wantOut[0], wantOut[1], wantOut[2], wantOut[3])
+	if got := mismatched.Sum(nil); !bytes.Equal(got, want) {
+		t.Errorf("Sum() after unmarshal = %x, want %x", got, want)
+	}
+
+	// ...but Reset rebuilds state from d.key, which is still otherKey, not
+	// the streamKey the marshaled state was produced under.
+	mismatched.Reset()
+	afterReset := mismatched.Sum(nil)
+	wrongOut := Hash256(otherKey, nil)
+	wrongWant := lanesBytes(wrongOut[0], wrongOut[1], wrongOut[2], wrongOut[3])
+	if !bytes.Equal(afterReset, wrongWant) {
+		t.Errorf("Sum() after Reset = %x, want %x (reinitialized from otherKey, not streamKey)", afterReset, wrongWant)
+	}
+}
@@ -0,0 +1,99 @@
+package highway
+
+import "testing"
+
+// These are golden vectors for Hash/Hash128/Hash256 over key = bytes
+// 0..31 and messages data[i] = byte(i) of the given length, captured
+// from this package and cross-checked against an independent Python
+// reimplementation of the same algorithm. They catch regressions in the
+// scalar path and, via the *MatchesGeneric tests in
+// state_amd64_test.go/state_arm64_test.go, in the SIMD backends.
+//
+// IMPORTANT: these are NOT the upstream google/highwayhash
+// kTestHash64/kTestHash128/kTestHash256 vectors the original request
+// asked for. The reference source was unreachable from this environment
+// (no network access) so interop with the reference implementation is
+// still unverified; swap these for the real published vectors before
+// relying on this package for cross-implementation compatibility (e.g.
+// the bitrot subpackage talking to a non-Go HighwayHash-256 writer).
+var vectorKey = Lanes{0x0706050403020100, 0x0f0e0d0c0b0a0908, 0x1716151413121110, 0x1f1e1d1c1b1a1918}
+
+func vectorData(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func TestHashVectors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want uint64
+	}{
+		{0, 0xe59e60a55ba25cca},
+		{1, 0x0eaff68bcbebbfb8},
+		{3, 0x6b2a247687f60d46},
+		{8, 0xeb899858319e72f6},
+		{31, 0xd9e1d4354a6251e2},
+		{32, 0xb49e53a4ccb6530b},
+		{63, 0x250be1ccd36ebcb7},
+		{64, 0x9c7054dbf83ad0c6},
+		{127, 0x6975aac6e07837ed},
+		{128, 0xa8bcff4df1ae616e},
+	}
+
+	for _, c := range cases {
+		if got := Hash(vectorKey, vectorData(c.n)); got != c.want {
+			t.Errorf("Hash(len=%d) = %#x, want %#x", c.n, got, c.want)
+		}
+	}
+}
+
+func TestHash128Vectors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want [2]uint64
+	}{
+		{0, [2]uint64{0x99c0c38bdd327816, 0x7c2cd493b3691788}},
+		{1, [2]uint64{0x9f9229b783bd2f61, 0xb6bd3f5b51f5672c}},
+		{3, [2]uint64{0x0f64be3d34f76161, 0xe534de85400c2ecb}},
+		{8, [2]uint64{0x4fa0a6129eb9602e, 0x05d46c925be5c1f6}},
+		{31, [2]uint64{0xd448e1a0d86f88b7, 0x78cc3c1ace239e4a}},
+		{32, [2]uint64{0x5ea16b4b9c1731fe, 0xc429d9c11ac73398}},
+		{63, [2]uint64{0xa7cf70376551a7c0, 0x8154f10f891b9355}},
+		{64, [2]uint64{0xa726a99b65d87b72, 0x9a0e2dd1aed38258}},
+		{127, [2]uint64{0xa6fbba15c8e54c7c, 0xcb2cbfb074f2d95a}},
+		{128, [2]uint64{0xf115001641937056, 0x06965faa56b8c66a}},
+	}
+
+	for _, c := range cases {
+		if got := Hash128(vectorKey, vectorData(c.n)); got != c.want {
+			t.Errorf("Hash128(len=%d) = %#x, want %#x", c.n, got, c.want)
+		}
+	}
+}
+
+func TestHash256Vectors(t *testing.T) {
+	cases := []struct {
+		n    int
+		want [4]uint64
+	}{
+		{0, [4]uint64{0x1a1d09c80559b94a, 0x3921a2cf06ffc5a3, 0x7f2eb3d8b14d3737, 0xc1bb2287fadb058e}},
+		{1, [4]uint64{0x9289eae3092fd9ce, 0x9289fb7d288ce17c, 0x570a8c246df5a03c, 0xe2867718f012a5ce}},
+		{3, [4]uint64{0x36ae80eae5012e7f, 0x0e9f1183db530761, 0xc4b10c47d69a47e6, 0xfe4df0617369c8bd}},
+		{8, [4]uint64{0xd5da57451dcfb7d8, 0x31596fe8a126e2d5, 0xc8cf47cfc2a2a38e, 0x08ef9e1e05dcf3e5}},
+		{31, [4]uint64{0x83fdcb1bcb0ded87, 0x70d9c708e89592b2, 0xd900288a167fe3d5, 0xa6a6511ba7ab3892}},
+		{32, [4]uint64{0x0405115cbde4f59d, 0x13591c6b030ad99a, 0xbdede62fedb0dce1, 0x706bf3cd9f254a0d}},
+		{63, [4]uint64{0xbf70a21958ae305d, 0xa7483dcbb288bb94, 0x1aae18fe4fd8d53d, 0x7731e38df0dc695c}},
+		{64, [4]uint64{0x51112049205a8d61, 0xe62e4cfba71637d3, 0xac819ca653051e47, 0x62c6b4d860fd1ff6}},
+		{127, [4]uint64{0x1fbccdb19fec57e4, 0xfd77bc43111123b4, 0x3a4198f78cb38fa2, 0xc84e8fcf110d262d}},
+		{128, [4]uint64{0xa55dc17a4d514f99, 0x73441537b33376df, 0x024246e8ad4c7be4, 0x8d20773e6905bd93}},
+	}
+
+	for _, c := range cases {
+		if got := Hash256(vectorKey, vectorData(c.n)); got != c.want {
+			t.Errorf("Hash256(len=%d) = %#x, want %#x", c.n, got, c.want)
+		}
+	}
+}